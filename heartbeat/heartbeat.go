@@ -4,6 +4,8 @@ Package heartbeat implements various heartbeat generators
 package heartbeat
 
 import (
+	"context"
+	"sync"
 	"time"
 )
 
@@ -40,3 +42,99 @@ func Beatn(n int) (<-chan Beat, <-chan struct{}) {
 	}()
 	return hb, done
 }
+
+// Pacemaker beats at a fixed pulse width and requires the consumer to acknowledge each beat
+// via Echo. Unlike BeatUntil, a Pacemaker can tell the difference between a peer that is
+// slow and a peer that is gone: if a beat goes unechoed for longer than 2*pulse, the peer is
+// considered dead, Dead() is closed, and the optional onDead callback is invoked.
+type Pacemaker struct {
+	pulse  time.Duration
+	onDead func() error
+	beat   chan Beat
+	echo   chan struct{}
+	dead   chan struct{}
+	stop   chan struct{}
+	once   sync.Once
+}
+
+// NewPacemaker returns a Pacemaker that beats every pulse and calls onDead once the
+// consumer stops echoing beats
+func NewPacemaker(pulse time.Duration, onDead func() error) *Pacemaker {
+	return &Pacemaker{
+		pulse:  pulse,
+		onDead: onDead,
+		beat:   make(chan Beat),
+		echo:   make(chan struct{}),
+		dead:   make(chan struct{}),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Beat returns the channel the pacemaker beats on
+func (p *Pacemaker) Beat() <-chan Beat {
+	return p.beat
+}
+
+// Dead returns a channel that is closed once the peer is considered dead
+func (p *Pacemaker) Dead() <-chan struct{} {
+	return p.dead
+}
+
+// Echo acknowledges the most recent beat, proving the peer is still alive
+func (p *Pacemaker) Echo() {
+	select {
+	case p.echo <- struct{}{}:
+	case <-p.dead:
+	case <-p.stop:
+	}
+}
+
+// Stop halts the pacemaker without declaring the peer dead
+func (p *Pacemaker) Stop() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+}
+
+// Start beats until ctx is cancelled, Stop is called, or the peer is declared dead. The
+// pacemaker's beat channel is closed when Start returns. Handing off a beat is always
+// non-blocking: a peer that is too slow (or stuck) to receive is exactly the condition this
+// is meant to diagnose, so the staleness check below must keep running off ticker.C
+// regardless of whether the last beat was ever picked up.
+func (p *Pacemaker) Start(ctx context.Context) error {
+	var (
+		ticker   = time.NewTicker(p.pulse)
+		sentBeat time.Time
+		echoBeat = time.Now()
+	)
+	defer ticker.Stop()
+	defer close(p.beat)
+
+	declareDead := func() error {
+		close(p.dead)
+		if p.onDead != nil {
+			return p.onDead()
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.stop:
+			return nil
+		case <-p.echo:
+			echoBeat = time.Now()
+		case <-ticker.C:
+			if !sentBeat.IsZero() && sentBeat.Sub(echoBeat) > 2*p.pulse {
+				return declareDead()
+			}
+			sentBeat = time.Now()
+			select {
+			case p.beat <- Beat{}:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPacemakerDetectsADeadConsumer(t *testing.T) {
+	var (
+		pulse        = 10 * time.Millisecond
+		onDeadCalled = make(chan struct{})
+		pm           = NewPacemaker(pulse, func() error {
+			close(onDeadCalled)
+			return nil
+		})
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	)
+	defer cancel()
+
+	go pm.Start(ctx)
+
+	// receive and echo exactly one beat, then stop reading entirely, simulating a consumer
+	// that gets stuck handing its own result off downstream
+	<-pm.Beat()
+	pm.Echo()
+
+	select {
+	case <-pm.Dead():
+	case <-ctx.Done():
+		t.Fatalf("expected pacemaker to detect a dead peer")
+	}
+
+	select {
+	case <-onDeadCalled:
+	case <-ctx.Done():
+		t.Fatalf("expected onDead callback to run")
+	}
+}
+
+func TestPacemakerStaysAliveWhileEchoed(t *testing.T) {
+	var (
+		pulse       = 10 * time.Millisecond
+		pm          = NewPacemaker(pulse, nil)
+		ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	)
+	defer cancel()
+
+	go pm.Start(ctx)
+
+	go func() {
+		for range pm.Beat() {
+			pm.Echo()
+		}
+	}()
+
+	select {
+	case <-pm.Dead():
+		t.Fatalf("expected pacemaker not to declare a peer dead while echoes keep arriving")
+	case <-ctx.Done():
+	}
+}
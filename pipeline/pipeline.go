@@ -0,0 +1,112 @@
+/*
+Package pipeline implements small generic combinators for composing channel-based
+processing graphs on top of heartbeat-driven sources. Combinators close their output
+channel once their input is exhausted. Map, Filter, Take and Merge take no done channel:
+each runs until its input is closed, and its goroutine blocks on send for as long as
+nobody reads its output. OrDone stops its own goroutine once done is closed; it does not
+stop the goroutines that feed its input.
+*/
+package pipeline
+
+import "sync"
+
+// Map applies f to every value read from in, closing its output when in is closed
+func Map[A, B any](in <-chan A, f func(A) B) <-chan B {
+	out := make(chan B)
+
+	go func() {
+		defer close(out)
+		for a := range in {
+			out <- f(a)
+		}
+	}()
+
+	return out
+}
+
+// Filter forwards only the values from in for which pred returns true
+func Filter[T any](in <-chan T, pred func(T) bool) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+		for v := range in {
+			if pred(v) {
+				out <- v
+			}
+		}
+	}()
+
+	return out
+}
+
+// Take forwards at most n values read from in, then closes its output and stops reading
+func Take[T any](in <-chan T, n int) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+		for i := 0; i < n; i++ {
+			v, ok := <-in
+			if !ok {
+				return
+			}
+			out <- v
+		}
+	}()
+
+	return out
+}
+
+// Merge fans in every channel in ins onto a single output channel, closing it once every
+// input channel has closed
+func Merge[T any](ins ...<-chan T) <-chan T {
+	var (
+		out = make(chan T)
+		wg  sync.WaitGroup
+	)
+
+	wg.Add(len(ins))
+	for _, in := range ins {
+		go func(in <-chan T) {
+			defer wg.Done()
+			for v := range in {
+				out <- v
+			}
+		}(in)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// OrDone wraps in so that ranging over the result also stops once done is closed. Only
+// OrDone's own goroutine exits on done; whatever is sending on in is left as it is.
+func OrDone[T any](done <-chan struct{}, in <-chan T) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-done:
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,79 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMapFilterTake(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 1; i <= 10; i++ {
+			in <- i
+		}
+	}()
+
+	var (
+		doubled = Map(in, func(i int) int { return i * 2 })
+		even    = Filter(doubled, func(i int) bool { return i%4 == 0 })
+		got     []int
+	)
+
+	for v := range Take(even, 3) {
+		got = append(got, v)
+	}
+
+	want := []int{4, 8, 12}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := make(chan int)
+	b := make(chan int)
+
+	go func() {
+		defer close(a)
+		a <- 1
+		a <- 2
+	}()
+	go func() {
+		defer close(b)
+		b <- 3
+		b <- 4
+	}()
+
+	gotCount := 0
+	for range Merge(a, b) {
+		gotCount++
+	}
+
+	if gotCount != 4 {
+		t.Fatalf("got %d values, want 4", gotCount)
+	}
+}
+
+func TestOrDoneStopsOnDone(t *testing.T) {
+	var (
+		in   = make(chan int)
+		done = make(chan struct{})
+		out  = OrDone(done, in)
+	)
+	close(done)
+
+	select {
+	case _, open := <-out:
+		if open {
+			t.Fatalf("expected output channel to be closed once done is closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected OrDone to close its output promptly once done is closed")
+	}
+}
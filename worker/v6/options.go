@@ -0,0 +1,22 @@
+package v6
+
+import "github.com/mstreet3/depinject/heartbeat"
+
+// option is a private struct of the package that holds optional randIntStream behavior
+type option struct {
+	pacemaker *heartbeat.Pacemaker
+}
+
+// by default no pacemaker is configured
+func newOption() *option {
+	return &option{}
+}
+
+// WithPacemaker replaces the stream's scheduling heartbeat with a Pacemaker, so a slow
+// consumer of values is detected as a dead peer instead of silently blocking the worker
+// forever
+func WithPacemaker(pm *heartbeat.Pacemaker) func(*option) {
+	return func(o *option) {
+		o.pacemaker = pm
+	}
+}
@@ -0,0 +1,38 @@
+package v6
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mstreet3/depinject/heartbeat"
+)
+
+func TestPacemakerStopsTheStreamOnADeadConsumer(t *testing.T) {
+	var (
+		pulse       = 10 * time.Millisecond
+		pm          = heartbeat.NewPacemaker(pulse, nil)
+		ris, err    = NewRandIntStreamf(make(chan heartbeat.Beat), WithPacemaker(pm))
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	)
+	defer cancel()
+
+	if err != nil {
+		t.Fatalf("unexpected constructor error: %s", err.Error())
+	}
+
+	values := ris.Start(ctx)
+
+	// never drain a value or echo a beat, so the pacemaker is left to declare the consumer
+	// dead on its own
+	time.Sleep(20 * pulse)
+
+	select {
+	case _, open := <-values:
+		if open {
+			t.Fatalf("expected the stream to stop once the pacemaker declares the consumer dead")
+		}
+	case <-ctx.Done():
+		t.Fatalf("expected the stream to stop once the pacemaker declares the consumer dead")
+	}
+}
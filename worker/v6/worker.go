@@ -16,30 +16,49 @@ type config interface {
 
 type randIntStream struct {
 	config
-	hb <-chan heartbeat.Beat
+	hb       <-chan heartbeat.Beat
+	opts     *option
+	liveness chan heartbeat.Beat
 }
 
 // NewRandIntStreamf returns a rand int stream formatted via the provided heartbeat channel
-func NewRandIntStreamf(hb <-chan heartbeat.Beat) (*randIntStream, error) {
+func NewRandIntStreamf(hb <-chan heartbeat.Beat, opts ...func(*option)) (*randIntStream, error) {
 	if hb == nil {
 		return nil, errors.New("cannot provide a nil channel to constructor")
 	}
+
+	o := newOption()
+	for _, fn := range opts {
+		fn(o)
+	}
+
 	return &randIntStream{
-		hb: hb,
+		hb:   hb,
+		opts: o,
 	}, nil
 }
 
-func NewRandIntStream(cfg config) (*randIntStream, error) {
+func NewRandIntStream(cfg config, opts ...func(*option)) (*randIntStream, error) {
 	if cfg == nil {
 		return nil, errors.New("cannot provide a nil config to constructor")
 	}
+
+	o := newOption()
+	for _, fn := range opts {
+		fn(o)
+	}
+
 	return &randIntStream{
 		config: cfg,
+		opts:   o,
 	}, nil
 }
 
 // Start returns the stream of random integers, the stream has been shutdown if the returned channel is closed
 func (r *randIntStream) Start(ctx context.Context) <-chan int {
+	if r.opts != nil && r.opts.pacemaker != nil {
+		go r.opts.pacemaker.Start(ctx)
+	}
 	return r.worker(ctx.Done())
 }
 
@@ -48,6 +67,9 @@ func (r *randIntStream) worker(stop <-chan struct{}) <-chan int {
 	var (
 		values    = make(chan int)
 		heartbeat = r.getHeartbeat(stop)
+		liveness  = r.getLiveness()
+		pulse     = time.NewTicker(r.livenessInterval())
+		dead      = r.dead()
 		doWork    = func() int {
 			return rand.Int()
 		}
@@ -55,17 +77,34 @@ func (r *randIntStream) worker(stop <-chan struct{}) <-chan int {
 
 	go func() {
 		defer fmt.Println("done working!")
+		defer pulse.Stop()
 		defer close(values)
+		defer close(liveness)
 		for {
 			select {
 			case <-stop:
 				return
+			case <-dead:
+				fmt.Println("pacemaker declared the consumer dead, stopping!")
+				return
+			case <-pulse.C:
+				beat(liveness)
 			case <-heartbeat:
 				fmt.Println("doing work...")
-				select {
-				case values <- doWork():
-				case <-stop:
-					return
+				val := doWork()
+				for sent := false; !sent; {
+					select {
+					case values <- val:
+						sent = true
+						r.echo()
+					case <-stop:
+						return
+					case <-dead:
+						fmt.Println("pacemaker declared the consumer dead, stopping!")
+						return
+					case <-pulse.C:
+						beat(liveness)
+					}
 				}
 			}
 		}
@@ -74,11 +113,77 @@ func (r *randIntStream) worker(stop <-chan struct{}) <-chan int {
 	return values
 }
 
-// getHeartbeat assigns a default heartbeat for the stream if one has not already been provided
+// beat emits a heartbeat on hb without blocking, so a missed pulse never stalls the caller
+func beat(hb chan<- heartbeat.Beat) {
+	select {
+	case hb <- heartbeat.Beat{}:
+	default:
+	}
+}
+
+// getHeartbeat assigns a default heartbeat for the stream if one has not already been
+// provided. If a pacemaker is configured it takes over scheduling entirely, since it needs
+// to observe every beat's echo to detect a dead consumer.
 func (r *randIntStream) getHeartbeat(stop <-chan struct{}) <-chan heartbeat.Beat {
+	if r.opts != nil && r.opts.pacemaker != nil {
+		return r.opts.pacemaker.Beat()
+	}
+
 	if r.hb == nil {
 		r.hb = heartbeat.BeatUntil(stop, r.PulseWidth())
 	}
 
 	return r.hb
 }
+
+// echo acknowledges the most recent pacemaker beat, if a pacemaker is configured, proving
+// that the downstream consumer is still draining values
+func (r *randIntStream) echo() {
+	if r.opts != nil && r.opts.pacemaker != nil {
+		r.opts.pacemaker.Echo()
+	}
+}
+
+// dead returns the pacemaker's dead-peer channel, if a pacemaker is configured, so the
+// worker can stop itself once the pacemaker decides the downstream consumer is gone. A nil
+// channel blocks forever in a select, so this is a no-op when no pacemaker is configured.
+func (r *randIntStream) dead() <-chan struct{} {
+	if r.opts != nil && r.opts.pacemaker != nil {
+		return r.opts.pacemaker.Dead()
+	}
+	return nil
+}
+
+// defaultLivenessInterval is used to pulse liveness when the stream has no config to source
+// a pulse width from, e.g. when constructed via NewRandIntStreamf
+const defaultLivenessInterval = 250 * time.Millisecond
+
+// livenessInterval returns the interval to pulse liveness on. config is an embedded
+// interface that is nil unless the stream was built via NewRandIntStream, so PulseWidth
+// can only be called once config is known to be present.
+func (r *randIntStream) livenessInterval() time.Duration {
+	if r.config == nil {
+		return defaultLivenessInterval
+	}
+
+	return r.PulseWidth()
+}
+
+// getLiveness assigns a default liveness channel for the stream if one has not already been
+// created
+func (r *randIntStream) getLiveness() chan heartbeat.Beat {
+	if r.liveness == nil {
+		r.liveness = make(chan heartbeat.Beat)
+	}
+
+	return r.liveness
+}
+
+// Liveness returns a channel that pulses once per liveness interval, including while the
+// worker is blocked trying to hand off a value to a slow reader. Pulses are sent without
+// blocking, so a pulse is dropped when nobody is receiving and a slow liveness reader never
+// stalls the worker; tests and stewards can consume this channel to detect a livelocked
+// worker.
+func (r *randIntStream) Liveness() <-chan heartbeat.Beat {
+	return r.getLiveness()
+}
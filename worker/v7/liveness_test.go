@@ -0,0 +1,35 @@
+package v7
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mstreet3/depinject/heartbeat"
+)
+
+func TestLivenessPulsesWhileBlockedOnSend(t *testing.T) {
+	var (
+		hb, _       = heartbeat.Beatn(1)
+		ris, err    = NewResultStreamf(hb, ones{})
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	)
+
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected constructor error: %s", err.Error())
+	}
+
+	// start the worker but never drain values, so it gets stuck trying to hand off the
+	// first result
+	ris.Start(ctx)
+
+	select {
+	case <-ris.Liveness():
+	case <-ctx.Done():
+		t.Fatalf("expected a liveness pulse while the worker is blocked on send")
+	}
+}
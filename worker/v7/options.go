@@ -0,0 +1,37 @@
+package v7
+
+import (
+	"time"
+
+	"github.com/mstreet3/depinject/heartbeat"
+)
+
+// option is a private struct of the package that holds optional resultStream behavior
+type option struct {
+	useSteward     bool
+	stewardTimeout time.Duration
+	pacemaker      *heartbeat.Pacemaker
+}
+
+// by default no steward or pacemaker is configured
+func newOption() *option {
+	return &option{}
+}
+
+// WithSteward runs resultStream.Start under a steward that supervises a heartbeat, restarting
+// it if no beat is observed within timeout, and schedules the stream on the steward's own beat
+func WithSteward(timeout time.Duration) func(*option) {
+	return func(o *option) {
+		o.useSteward = true
+		o.stewardTimeout = timeout
+	}
+}
+
+// WithPacemaker replaces the stream's scheduling heartbeat with a Pacemaker, so a slow
+// consumer of values is detected as a dead peer instead of silently blocking the worker
+// forever
+func WithPacemaker(pm *heartbeat.Pacemaker) func(*option) {
+	return func(o *option) {
+		o.pacemaker = pm
+	}
+}
@@ -0,0 +1,34 @@
+package v7
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mstreet3/depinject/heartbeat"
+)
+
+func TestPacemakerStopsTheStreamOnADeadConsumer(t *testing.T) {
+	var (
+		pulse       = 10 * time.Millisecond
+		pm          = heartbeat.NewPacemaker(pulse, nil)
+		ris         = &resultStream[int]{worker: ones{}, opts: &option{pacemaker: pm}}
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	)
+	defer cancel()
+
+	values := ris.Start(ctx)
+
+	// never drain a value or echo a beat, so the pacemaker is left to declare the consumer
+	// dead on its own
+	time.Sleep(20 * pulse)
+
+	select {
+	case _, open := <-values:
+		if open {
+			t.Fatalf("expected the stream to stop once the pacemaker declares the consumer dead")
+		}
+	case <-ctx.Done():
+		t.Fatalf("expected the stream to stop once the pacemaker declares the consumer dead")
+	}
+}
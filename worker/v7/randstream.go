@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/mstreet3/depinject/heartbeat"
@@ -13,59 +14,118 @@ type config interface {
 	PulseWidth() time.Duration
 }
 
-type resultStream[T int] struct {
+type resultStream[T any] struct {
 	config
 	hb <-chan heartbeat.Beat
 	worker[T]
+	opts     *option
+	liveness chan heartbeat.Beat
+	subsMu   sync.RWMutex
+	subs     []chan T
+	stopped  bool
 }
 
-// NewResultStreamf returns an int stream formatted via the provided heartbeat channel
-func NewResultStreamf(hb <-chan heartbeat.Beat) (*resultStream[int], error) {
+// NewResultStreamf returns a stream of w's results formatted via the provided heartbeat channel
+func NewResultStreamf[T any](hb <-chan heartbeat.Beat, w worker[T]) (*resultStream[T], error) {
 	if hb == nil {
 		return nil, errors.New("cannot provide a nil channel to constructor")
 	}
-	return &resultStream[int]{
+	if w == nil {
+		return nil, errors.New("cannot provide a nil worker to constructor")
+	}
+	return &resultStream[T]{
 		hb:     hb,
-		worker: randInt{},
+		worker: w,
 	}, nil
 }
 
-func NewResultStream(cfg config) (*resultStream[int], error) {
+func NewResultStream[T any](cfg config, w worker[T], opts ...func(*option)) (*resultStream[T], error) {
 	if cfg == nil {
 		return nil, errors.New("cannot provide a nil config to constructor")
 	}
-	return &resultStream[int]{
+	if w == nil {
+		return nil, errors.New("cannot provide a nil worker to constructor")
+	}
+
+	o := newOption()
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	return &resultStream[T]{
 		config: cfg,
-		worker: randInt{},
+		worker: w,
+		opts:   o,
 	}, nil
 }
 
-// Start returns the stream of random integers, the stream has been shutdown if the returned channel is closed
-func (r *resultStream[int]) Start(ctx context.Context) <-chan int {
+// Start returns the stream of the worker's results, the stream has been shutdown if the returned channel is closed
+func (r *resultStream[T]) Start(ctx context.Context) <-chan T {
+	if r.opts != nil && r.opts.pacemaker != nil {
+		go r.opts.pacemaker.Start(ctx)
+	}
+	if r.opts != nil && r.opts.useSteward {
+		return r.serveUnderSteward(ctx.Done())
+	}
 	return r.serve(ctx.Done())
 }
 
+// serveUnderSteward runs a BeatUntil heartbeat as the ward of a steward, which restarts it
+// if it stalls for longer than the configured timeout. serve is scheduled by the steward's
+// own heartbeat, which beats every PulseWidth; the ward's beats are not forwarded to serve.
+func (r *resultStream[T]) serveUnderSteward(stop <-chan struct{}) <-chan T {
+	ward := func(done <-chan struct{}, pulseInterval time.Duration) <-chan heartbeat.Beat {
+		return heartbeat.BeatUntil(done, pulseInterval)
+	}
+
+	st := newSteward(ward, r.opts.stewardTimeout)
+	r.hb = st.start(stop, r.PulseWidth())
+
+	return r.serve(stop)
+}
+
 // worker calls does some arbitrary work with each heartbeat until it is told to stop
-func (r *resultStream[int]) serve(stop <-chan struct{}) <-chan int {
+func (r *resultStream[T]) serve(stop <-chan struct{}) <-chan T {
 	var (
-		values    = make(chan int)
+		values    = make(chan T)
 		heartbeat = r.getHeartbeat(stop)
+		liveness  = r.getLiveness()
+		pulse     = time.NewTicker(r.livenessInterval())
+		dead      = r.dead()
 	)
 
 	go func() {
 		defer fmt.Println("done working!")
+		defer pulse.Stop()
 		defer close(values)
+		defer close(liveness)
+		defer r.closeSubs()
 		for {
 			select {
 			case <-stop:
 				return
+			case <-dead:
+				fmt.Println("pacemaker declared the consumer dead, stopping!")
+				return
+			case <-pulse.C:
+				beat(liveness)
 			case <-heartbeat:
 				fmt.Println("doing work...")
 				val, _ := r.work()
-				select {
-				case values <- val:
-				case <-stop:
-					return
+				for sent := false; !sent; {
+					select {
+					case values <- val:
+						sent = true
+						r.echo()
+						r.publish(val)
+					case <-stop:
+						return
+					case <-dead:
+						fmt.Println("pacemaker declared the consumer dead, stopping!")
+						return
+					case <-pulse.C:
+						beat(liveness)
+					}
 				}
 			}
 		}
@@ -74,11 +134,153 @@ func (r *resultStream[int]) serve(stop <-chan struct{}) <-chan int {
 	return values
 }
 
-// getHeartbeat assigns a default heartbeat for the stream if one has not already been provided
-func (r *resultStream[int]) getHeartbeat(stop <-chan struct{}) <-chan heartbeat.Beat {
+// Subscribe registers a new listener that receives a copy of every value produced by the
+// stream. Sends to the listener are non-blocking, so a slow subscriber is never able to
+// backpressure the producer and instead just misses values. Subscribe must be called while
+// the stream is running (or before it starts); once the stream has exited, Subscribe
+// returns an already-closed channel instead of registering a listener that would never be
+// published to or closed.
+func (r *resultStream[T]) Subscribe() <-chan T {
+	return r.subscribe(0)
+}
+
+// SubscribeBuffered registers a new listener with a buffer of n, guaranteeing delivery of up
+// to n in-flight values before sends start dropping for a slow subscriber. Like Subscribe,
+// it returns an already-closed channel if the stream has already exited.
+func (r *resultStream[T]) SubscribeBuffered(n int) <-chan T {
+	return r.subscribe(n)
+}
+
+func (r *resultStream[T]) subscribe(n int) <-chan T {
+	ch := make(chan T, n)
+
+	r.subsMu.Lock()
+	defer r.subsMu.Unlock()
+
+	if r.stopped {
+		close(ch)
+		return ch
+	}
+
+	r.subs = append(r.subs, ch)
+
+	return ch
+}
+
+// Unsubscribe removes and closes a previously registered subscriber channel. It is a no-op
+// if ch was not returned by Subscribe or SubscribeBuffered, or was already unsubscribed.
+func (r *resultStream[T]) Unsubscribe(ch <-chan T) {
+	r.subsMu.Lock()
+	defer r.subsMu.Unlock()
+
+	for i, sub := range r.subs {
+		if sub == ch {
+			close(sub)
+			r.subs = append(r.subs[:i], r.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// publish fans a value out to every active subscriber via a non-blocking send so a slow
+// subscriber can never backpressure the producer
+func (r *resultStream[T]) publish(val T) {
+	r.subsMu.RLock()
+	defer r.subsMu.RUnlock()
+
+	for _, sub := range r.subs {
+		select {
+		case sub <- val:
+		default:
+		}
+	}
+}
+
+// closeSubs closes and clears every registered subscriber channel and marks the stream as
+// stopped, so any later call to subscribe returns an already-closed channel instead of a
+// listener nothing will ever publish to or close
+func (r *resultStream[T]) closeSubs() {
+	r.subsMu.Lock()
+	defer r.subsMu.Unlock()
+
+	for _, sub := range r.subs {
+		close(sub)
+	}
+	r.subs = nil
+	r.stopped = true
+}
+
+// beat emits a heartbeat on hb without blocking, so a missed pulse never stalls the caller
+func beat(hb chan<- heartbeat.Beat) {
+	select {
+	case hb <- heartbeat.Beat{}:
+	default:
+	}
+}
+
+// getHeartbeat assigns a default heartbeat for the stream if one has not already been
+// provided. If a pacemaker is configured it takes over scheduling entirely, since it needs
+// to observe every beat's echo to detect a dead consumer.
+func (r *resultStream[T]) getHeartbeat(stop <-chan struct{}) <-chan heartbeat.Beat {
+	if r.opts != nil && r.opts.pacemaker != nil {
+		return r.opts.pacemaker.Beat()
+	}
+
 	if r.hb == nil {
 		r.hb = heartbeat.BeatUntil(stop, r.PulseWidth())
 	}
 
 	return r.hb
 }
+
+// echo acknowledges the most recent pacemaker beat, if a pacemaker is configured, proving
+// that the downstream consumer is still draining values
+func (r *resultStream[T]) echo() {
+	if r.opts != nil && r.opts.pacemaker != nil {
+		r.opts.pacemaker.Echo()
+	}
+}
+
+// dead returns the pacemaker's dead-peer channel, if a pacemaker is configured, so the
+// worker can stop itself once the pacemaker decides the downstream consumer is gone. A nil
+// channel blocks forever in a select, so this is a no-op when no pacemaker is configured.
+func (r *resultStream[T]) dead() <-chan struct{} {
+	if r.opts != nil && r.opts.pacemaker != nil {
+		return r.opts.pacemaker.Dead()
+	}
+	return nil
+}
+
+// defaultLivenessInterval is used to pulse liveness when the stream has no config to source
+// a pulse width from, e.g. when constructed via NewResultStreamf
+const defaultLivenessInterval = 250 * time.Millisecond
+
+// livenessInterval returns the interval to pulse liveness on. config is an embedded
+// interface that is nil unless the stream was built via NewResultStream, so PulseWidth can
+// only be called once config is known to be present.
+func (r *resultStream[T]) livenessInterval() time.Duration {
+	if r.config == nil {
+		return defaultLivenessInterval
+	}
+
+	return r.PulseWidth()
+}
+
+// getLiveness assigns a default liveness channel for the stream if one has not already been
+// created
+func (r *resultStream[T]) getLiveness() chan heartbeat.Beat {
+	if r.liveness == nil {
+		r.liveness = make(chan heartbeat.Beat)
+	}
+
+	return r.liveness
+}
+
+// Liveness returns a channel that pulses once per liveness interval, including while the
+// worker is blocked trying to hand off a value to a slow reader. Pulses are sent without
+// blocking, so a pulse is dropped when nobody is receiving and a slow liveness reader never
+// stalls the worker; tests and stewards can consume this channel to detect a livelocked
+// worker.
+func (r *resultStream[T]) Liveness() <-chan heartbeat.Beat {
+	return r.getLiveness()
+}
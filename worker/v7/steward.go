@@ -0,0 +1,83 @@
+package v7
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mstreet3/depinject/heartbeat"
+)
+
+// startGoroutineFn starts a goroutine that beats on the returned channel until done is
+// closed, matching the shape of the ward that resultStream.serveUnderSteward supervises.
+type startGoroutineFn func(done <-chan struct{}, pulseInterval time.Duration) <-chan heartbeat.Beat
+
+// steward supervises a ward goroutine, restarting it whenever it misses a heartbeat for
+// longer than timeout. A steward's start method is itself a startGoroutineFn, so stewards
+// can be composed to supervise other stewards.
+type steward struct {
+	ward    startGoroutineFn
+	timeout time.Duration
+}
+
+// newSteward returns a steward that restarts ward if it stops beating for timeout
+func newSteward(ward startGoroutineFn, timeout time.Duration) *steward {
+	return &steward{
+		ward:    ward,
+		timeout: timeout,
+	}
+}
+
+// start launches and supervises the ward until done is closed, returning the steward's own
+// heartbeat so the steward itself can be supervised
+func (s *steward) start(done <-chan struct{}, pulseInterval time.Duration) <-chan heartbeat.Beat {
+	var (
+		hb        = make(chan heartbeat.Beat)
+		wardDone  = make(chan struct{})
+		wardHb    = s.ward(wardDone, pulseInterval)
+		pulse     = time.NewTicker(pulseInterval)
+		unhealthy = time.NewTimer(s.timeout)
+	)
+
+	stopWard := func() {
+		close(wardDone)
+		for range wardHb {
+			// drain in-flight beats before the ward is considered stopped
+		}
+	}
+
+	restart := func() {
+		fmt.Println("ward is unhealthy, restarting...")
+		stopWard()
+		wardDone = make(chan struct{})
+		wardHb = s.ward(wardDone, pulseInterval)
+		unhealthy.Reset(s.timeout)
+	}
+
+	go func() {
+		defer close(hb)
+		defer pulse.Stop()
+		defer unhealthy.Stop()
+
+		for {
+			select {
+			case <-done:
+				stopWard()
+				return
+			case <-pulse.C:
+				beat(hb)
+			case _, ok := <-wardHb:
+				if !ok {
+					return
+				}
+				if !unhealthy.Stop() {
+					<-unhealthy.C
+				}
+				unhealthy.Reset(s.timeout)
+			case <-unhealthy.C:
+				restart()
+			}
+		}
+	}()
+
+	return hb
+}
@@ -0,0 +1,64 @@
+package v7
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mstreet3/depinject/heartbeat"
+)
+
+func TestStewardRestartsAStalledWard(t *testing.T) {
+	var (
+		restarts int32
+		ward     = func(done <-chan struct{}, pulseInterval time.Duration) <-chan heartbeat.Beat {
+			hb := make(chan heartbeat.Beat)
+
+			if atomic.AddInt32(&restarts, 1) == 1 {
+				// the first ward never beats, simulating a stalled goroutine
+				go func() {
+					<-done
+					close(hb)
+				}()
+				return hb
+			}
+
+			go func() {
+				defer close(hb)
+				ticker := time.NewTicker(pulseInterval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+						select {
+						case hb <- heartbeat.Beat{}:
+						case <-done:
+							return
+						}
+					}
+				}
+			}()
+
+			return hb
+		}
+		st   = newSteward(ward, 30*time.Millisecond)
+		done = make(chan struct{})
+	)
+
+	t.Cleanup(func() {
+		close(done)
+	})
+
+	st.start(done, 10*time.Millisecond)
+
+	deadline := time.After(time.Second)
+	for atomic.LoadInt32(&restarts) < 2 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected steward to restart the stalled ward, ward started %d time(s)", atomic.LoadInt32(&restarts))
+		default:
+		}
+	}
+}
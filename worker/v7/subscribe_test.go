@@ -0,0 +1,90 @@
+package v7
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mstreet3/depinject/heartbeat"
+)
+
+func TestSubscribeFansOutValues(t *testing.T) {
+	var (
+		wantCount   = 5
+		gotCount    = 0
+		hb, _       = heartbeat.Beatn(wantCount)
+		ris         = &resultStream[int]{hb: hb, worker: ones{}}
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	)
+	defer cancel()
+
+	sub := ris.SubscribeBuffered(wantCount)
+	values := ris.Start(ctx)
+
+	go func() {
+		for range values {
+		}
+	}()
+
+	for gotCount != wantCount {
+		select {
+		case <-sub:
+			gotCount++
+		case <-ctx.Done():
+			t.Fatalf("unexpected timeout waiting for subscriber to receive values")
+		}
+	}
+}
+
+func TestUnsubscribeClosesTheChannel(t *testing.T) {
+	var (
+		hb          = make(chan heartbeat.Beat)
+		ris         = &resultStream[int]{hb: hb, worker: ones{}}
+		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	)
+	defer cancel()
+
+	sub := ris.Subscribe()
+	ris.Unsubscribe(sub)
+
+	select {
+	case _, open := <-sub:
+		if open {
+			t.Fatalf("expected unsubscribed channel to be closed")
+		}
+	case <-ctx.Done():
+		t.Fatalf("expected unsubscribed channel to close promptly")
+	}
+}
+
+func TestSubscribeAfterShutdownReturnsAClosedChannel(t *testing.T) {
+	var (
+		hb          = make(chan heartbeat.Beat) // never beats, so serve only ever exits via stop
+		ris         = &resultStream[int]{hb: hb, worker: ones{}}
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+	defer cancel()
+
+	values := ris.Start(ctx)
+	cancel()
+
+	select {
+	case _, open := <-values:
+		if open {
+			t.Fatalf("expected stream to shut down once cancelled")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected stream to shut down promptly")
+	}
+
+	sub := ris.Subscribe()
+
+	select {
+	case _, open := <-sub:
+		if open {
+			t.Fatalf("expected a subscription made after shutdown to be pre-closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected a subscription made after shutdown to be closed immediately")
+	}
+}